@@ -15,6 +15,11 @@ STREAM_CONFIG_PATH="/etc/supervisor/conf.d/stream.conf"
 STREAM_LOG_PATH="/var/log/ffmpeg/stream.log"
 SUPERVISOR_CONFIG_PATH="/etc/supervisor/supervisord.conf"
 
+# Default SRT parameters, custom parameters from the allowlist override these
+SRT_PARAM_KEYS=("pkt_size" "oheadbw" "maxbw" "latency" "mode" "transtype")
+declare -A SRT_PARAMS=([pkt_size]=1316 [oheadbw]=100 [maxbw]=-1 [latency]=5000000 [mode]=caller [transtype]=live)
+SRT_ALLOWED_PARAMS=("connect_timeout" "ffs" "inputbw" "latency" "maxbw" "nakreport" "oheadbw" "payload_size" "peerlatency" "pkt_size" "rcvbuf" "rcvlatency" "sndbuf" "tlpktdrop")
+
 # General Raspberry Pi configuration
 CONFIG_FILE_PATHS=("/boot/firmware/config.txt" "/boot/config.txt")
 FIRST_IP=$(hostname -I | awk '{print $1}')
@@ -67,8 +72,16 @@ EOF
 # Greeting
 echo -e "${GREEN}⎎ Audio encoder set-up for Raspberry Pi${NC}\n"
 
+# Ask for the audio source first, a test tone does not need a HiFiBerry
+ask_user "AUDIO_INPUT" "hifiberry" "Choose audio input: hifiberry or testtone" "str"
+
+if ! [[ "$AUDIO_INPUT" =~ ^(hifiberry|testtone)$ ]]; then
+  echo "Invalid input for AUDIO_INPUT. Only 'hifiberry' or 'testtone' are allowed."
+  exit 1
+fi
+
 # Check if the HiFiBerry is configured
-if ! grep -q "^dtoverlay=hifiberry" "$CONFIG_FILE"; then
+if [ "$AUDIO_INPUT" == "hifiberry" ] && ! grep -q "^dtoverlay=hifiberry" "$CONFIG_FILE"; then
   echo -e "${RED}No HiFiBerry card configured in the $CONFIG_FILE file. Exiting...${NC}\n" >&2
   exit 1
 fi
@@ -78,24 +91,203 @@ ask_user "DO_UPDATES" "y" "Do you want to perform all OS updates? (y/n)" "y/n"
 ask_user "SAVE_OUTPUT" "y" "Do you want to save the output of ffmpeg to a log file? (y/n)" "y/n"
 ask_user "ENABLE_HEARTBEAT" "n" "Do you want to integrate heartbeat monitoring via UptimeRobot (y/n)" "y/n"
 if [ "$ENABLE_HEARTBEAT" == "y" ]; then
-  ask_user "HEARTBEAT_URL" "https://heartbeat.uptimerobot.com/xxx" "Enter the URL to get every minute for heartbeat monitoring" "str"
+  ask_user "HEARTBEAT_URL" "https://heartbeat.uptimerobot.com/xxx" "Enter the URL to get for heartbeat monitoring" "str"
+  ask_user "HEARTBEAT_INTERVAL" "1" "Enter the heartbeat interval in minutes (1 to 59)" "num"
 fi
 
 # Always ask these
 ask_user "WEB_PORT" "90" "Choose a port for the web interface" "num"
 ask_user "WEB_USER" "admin" "Choose a username for the web interface" "str"
 ask_user "WEB_PASSWORD" "encoder" "Choose a password for the web interface" "str"
+ask_user "INPUT_GAIN" "0" "Input gain in dB between -20 and +20" "str"
+ask_user "ENABLE_LIMITER" "n" "Do you want to apply a limiter before encoding? (y/n)" "y/n"
+if [ "$ENABLE_LIMITER" == "y" ]; then
+  ask_user "LIMITER_CEILING" "-1" "Limiter ceiling in dB between -20 and 0" "str"
+fi
 ask_user "OUTPUT_FORMAT" "wav" "Choose output format: mp2, mp3, ogg, or wav" "str"
-ask_user "STREAM_HOST" "localhost" "Hostname or IP address of SRT server" "str"
-ask_user "STREAM_PORT" "8080" "Port of SRT server" "num"
-ask_user "STREAM_PASSWORD" "hackme" "Password for SRT server" "str"
-ask_user "STREAM_MOUNTPOINT" "studio" "Stream ID for SRT server" "str"
+ask_user "OUTPUT_SAMPLE_RATE" "48000" "Choose output sample rate: 32000, 44100, or 48000" "num"
+ask_user "OUTPUT_MONO" "n" "Do you want to downmix the stream to mono? (y/n)" "y/n"
+ask_user "STREAM_PROTOCOL" "srt" "Choose streaming protocol: srt, rtmp, or udp" "str"
+
+if ! [[ "$STREAM_PROTOCOL" =~ ^(srt|rtmp|udp)$ ]]; then
+  echo "Invalid input for STREAM_PROTOCOL. Only 'srt', 'rtmp', or 'udp' are allowed."
+  exit 1
+fi
+
+# Ask the server details for the chosen protocol
+if [ "$STREAM_PROTOCOL" == "srt" ]; then
+  ask_user "STREAM_HOST" "localhost" "Hostname or IP address of SRT server" "str"
+  ask_user "STREAM_PORT" "8080" "Port of SRT server" "num"
+  ask_user "STREAM_PASSWORD" "hackmehackme" "Password for SRT server (10 to 79 characters)" "str"
+  ask_user "STREAM_PBKEYLEN" "16" "Encryption key length for SRT server: 16, 24, or 32" "num"
+  ask_user "STREAM_MOUNTPOINT" "studio" "Stream ID for SRT server" "str"
+  ask_user "STREAM_ID_MODE" "raw" "Stream ID format: raw, or structured (#!::r=<id>,m=publish)" "str"
+  ask_user "SRT_CUSTOM_PARAMS" "n" "Do you want to add or override SRT parameters? (y/n)" "y/n"
+  if [ "$SRT_CUSTOM_PARAMS" == "y" ]; then
+    ask_user "SRT_EXTRA_PARAMS" "rcvbuf=12058624&sndbuf=12058624" "Enter SRT parameters as key=value pairs separated by &" "str"
+  fi
+elif [ "$STREAM_PROTOCOL" == "rtmp" ]; then
+  ask_user "STREAM_HOST" "localhost" "Hostname or IP address of RTMP server" "str"
+  ask_user "STREAM_PORT" "1935" "Port of RTMP server" "num"
+  ask_user "RTMP_APP" "live" "Application name on RTMP server" "str"
+  ask_user "RTMP_STREAM_KEY" "studio" "Stream key for RTMP server" "str"
+elif [ "$STREAM_PROTOCOL" == "udp" ]; then
+  ask_user "STREAM_HOST" "239.0.0.1" "Unicast or multicast IP address for UDP stream" "str"
+  ask_user "STREAM_PORT" "5000" "Port for UDP stream" "num"
+  # Only multicast addresses (224.0.0.0/4) need a TTL
+  if [[ "$STREAM_HOST" =~ ^(22[4-9]|23[0-9])\. ]]; then
+    ask_user "UDP_TTL" "16" "Multicast TTL for UDP stream" "num"
+  fi
+fi
 
 if ! [[ "$OUTPUT_FORMAT" =~ ^(mp2|mp3|ogg|wav)$ ]]; then
   echo "Invalid input for OUTPUT_FORMAT. Only 'mp2', 'mp3', 'ogg', or 'wav' are allowed."
   exit 1
 fi
 
+if ! [[ "$INPUT_GAIN" =~ ^(0|[-+]?[1-9][0-9]?)$ ]] || (( INPUT_GAIN < -20 || INPUT_GAIN > 20 )); then
+  echo "Invalid input for INPUT_GAIN. Only whole numbers between -20 and +20 are allowed."
+  exit 1
+fi
+INPUT_GAIN="${INPUT_GAIN#+}"
+
+if [ "$ENABLE_LIMITER" == "y" ]; then
+  if ! [[ "$LIMITER_CEILING" =~ ^(0|-[1-9][0-9]?)$ ]] || (( LIMITER_CEILING < -20 )); then
+    echo "Invalid input for LIMITER_CEILING. Only whole numbers between -20 and 0 are allowed."
+    exit 1
+  fi
+fi
+
+if [ "$ENABLE_HEARTBEAT" == "y" ] && ! [[ "$HEARTBEAT_INTERVAL" =~ ^([1-9]|[1-5][0-9])$ ]]; then
+  echo "Invalid input for HEARTBEAT_INTERVAL. Only values between 1 and 59 are allowed."
+  exit 1
+fi
+
+if [ "$STREAM_PROTOCOL" == "srt" ]; then
+  # SRT silently refuses passphrases outside this range
+  if (( ${#STREAM_PASSWORD} < 10 || ${#STREAM_PASSWORD} > 79 )); then
+    echo "Invalid input for STREAM_PASSWORD. SRT requires a password between 10 and 79 characters."
+    exit 1
+  fi
+
+  if ! [[ "$STREAM_PBKEYLEN" =~ ^(16|24|32)$ ]]; then
+    echo "Invalid input for STREAM_PBKEYLEN. Only '16', '24', or '32' are allowed."
+    exit 1
+  fi
+
+  if ! [[ "$STREAM_ID_MODE" =~ ^(raw|structured)$ ]]; then
+    echo "Invalid input for STREAM_ID_MODE. Only 'raw' or 'structured' are allowed."
+    exit 1
+  fi
+
+  # Commas and equal signs separate the keys of a structured stream ID
+  if [ "$STREAM_ID_MODE" == "structured" ] && [[ "$STREAM_MOUNTPOINT" =~ [,=] ]]; then
+    echo "Invalid input for STREAM_MOUNTPOINT. A structured stream ID cannot contain ',' or '='."
+    exit 1
+  fi
+
+  # Merge the custom parameters into the defaults, all allowed parameters take integers
+  if [ "$SRT_CUSTOM_PARAMS" == "y" ]; then
+    IFS='&' read -ra SRT_EXTRA_PAIRS <<< "$SRT_EXTRA_PARAMS"
+    for pair in "${SRT_EXTRA_PAIRS[@]}"; do
+      key="${pair%%=*}"
+      value="${pair#*=}"
+      KEY_ALLOWED="n"
+      if [[ "$pair" == *=* ]] && [[ "$key" =~ ^[a-z_]+$ ]]; then
+        for allowed in "${SRT_ALLOWED_PARAMS[@]}"; do
+          if [ "$key" == "$allowed" ]; then
+            KEY_ALLOWED="y"
+          fi
+        done
+      fi
+      if [ "$KEY_ALLOWED" != "y" ] || ! [[ "$value" =~ ^-?[0-9]+$ ]]; then
+        echo "Invalid input for SRT_EXTRA_PARAMS. '$pair' is not supported. Allowed keys: ${SRT_ALLOWED_PARAMS[*]}."
+        exit 1
+      fi
+      if [ -z "${SRT_PARAMS[$key]+set}" ]; then
+        SRT_PARAM_KEYS+=("$key")
+      fi
+      SRT_PARAMS[$key]="$value"
+    done
+  fi
+fi
+
+# RTMP carries FLV, which of our formats only supports mp3
+if [ "$STREAM_PROTOCOL" == "rtmp" ] && [ "$OUTPUT_FORMAT" != "mp3" ]; then
+  echo "Invalid input for OUTPUT_FORMAT. Only 'mp3' is allowed when streaming over RTMP."
+  exit 1
+fi
+
+# FLV only signals 44100 Hz and its divisions for mp3, 48000 Hz is handled as a special case
+if [ "$STREAM_PROTOCOL" == "rtmp" ] && [ "$OUTPUT_SAMPLE_RATE" == "32000" ]; then
+  echo "Invalid input for OUTPUT_SAMPLE_RATE. Only '44100' or '48000' are allowed when streaming over RTMP."
+  exit 1
+fi
+
+# UDP carries MPEG-TS, which of our formats only supports mp2 and mp3
+if [ "$STREAM_PROTOCOL" == "udp" ] && ! [[ "$OUTPUT_FORMAT" =~ ^(mp2|mp3)$ ]]; then
+  echo "Invalid input for OUTPUT_FORMAT. Only 'mp2' or 'mp3' are allowed when streaming over UDP."
+  exit 1
+fi
+
+if [ -n "$UDP_TTL" ] && { ! [[ "$UDP_TTL" =~ ^[1-9][0-9]{0,2}$ ]] || (( UDP_TTL > 255 )); }; then
+  echo "Invalid input for UDP_TTL. Only values between 1 and 255 are allowed."
+  exit 1
+fi
+
+if ! [[ "$OUTPUT_SAMPLE_RATE" =~ ^(32000|44100|48000)$ ]]; then
+  echo "Invalid input for OUTPUT_SAMPLE_RATE. Only '32000', '44100', or '48000' are allowed."
+  exit 1
+fi
+
+# mp3 can use either a constant or a variable bitrate, never both
+if [ "$OUTPUT_FORMAT" == "mp3" ]; then
+  ask_user "USE_VBR" "n" "Do you want to use a variable bitrate for mp3? (y/n)" "y/n"
+fi
+
+# Ask for a bitrate or a quality level depending on the format
+if [ "$OUTPUT_FORMAT" == "mp2" ] && [ "$OUTPUT_MONO" == "y" ]; then
+  ask_user "OUTPUT_BITRATE" "192k" "Choose a bitrate for mono mp2 between 32k and 192k" "str"
+  # MPEG-1 Layer II single channel mode stops at 192 kbit/s
+  ALLOWED_BITRATES=(32 48 56 64 80 96 112 128 160 192)
+elif [ "$OUTPUT_FORMAT" == "mp2" ]; then
+  ask_user "OUTPUT_BITRATE" "384k" "Choose a bitrate for mp2 between 64k and 384k" "str"
+  # MPEG-1 Layer II only defines these bitrates for stereo, lower ones are single channel only
+  ALLOWED_BITRATES=(64 96 112 128 160 192 224 256 320 384)
+elif [ "$OUTPUT_FORMAT" == "mp3" ] && [ "$USE_VBR" == "y" ]; then
+  ask_user "OUTPUT_QUALITY" "0" "Choose a VBR quality for mp3 between 0 (best) and 9" "num"
+  MAX_QUALITY=9
+elif [ "$OUTPUT_FORMAT" == "mp3" ]; then
+  ask_user "OUTPUT_BITRATE" "320k" "Choose a bitrate for mp3 between 32k and 320k" "str"
+  # LAME snaps other values to the nearest one of these, so only accept them exactly
+  ALLOWED_BITRATES=(32 40 48 56 64 80 96 112 128 160 192 224 256 320)
+elif [ "$OUTPUT_FORMAT" == "ogg" ]; then
+  ask_user "OUTPUT_QUALITY" "10" "Choose a VBR quality for ogg between 0 and 10 (best)" "num"
+  MAX_QUALITY=10
+fi
+
+if [ -n "${ALLOWED_BITRATES[*]}" ]; then
+  BITRATE_ALLOWED="n"
+  if [[ "$OUTPUT_BITRATE" =~ ^[1-9][0-9]*k$ ]]; then
+    for bitrate in "${ALLOWED_BITRATES[@]}"; do
+      if [ "${OUTPUT_BITRATE%k}" == "$bitrate" ]; then
+        BITRATE_ALLOWED="y"
+      fi
+    done
+  fi
+  if [ "$BITRATE_ALLOWED" != "y" ]; then
+    echo "Invalid input for OUTPUT_BITRATE. Only these bitrates in kbit/s are allowed for $OUTPUT_FORMAT: ${ALLOWED_BITRATES[*]}."
+    exit 1
+  fi
+fi
+
+if [ -n "$MAX_QUALITY" ]; then
+  if ! [[ "$OUTPUT_QUALITY" =~ ^(0|[1-9][0-9]?)$ ]] || (( OUTPUT_QUALITY > MAX_QUALITY )); then
+    echo "Invalid input for OUTPUT_QUALITY. Only values between 0 and $MAX_QUALITY are allowed."
+    exit 1
+  fi
+fi
+
 # Timezone configuration
 set_timezone Europe/Amsterdam
 
@@ -144,15 +336,19 @@ fi
 
 # Set the ffmpeg variables based on the value of OUTPUT_FORMAT
 if [ "$OUTPUT_FORMAT" == "mp2" ]; then
-  FF_AUDIO_CODEC='libtwolame -b:a 384k -psymodel 4'
+  FF_AUDIO_CODEC="libtwolame -b:a $OUTPUT_BITRATE -psymodel 4"
   FF_CONTENT_TYPE='audio/mpeg'
   FF_OUTPUT_FORMAT='mp2'
 elif [ "$OUTPUT_FORMAT" == "mp3" ]; then
-  FF_AUDIO_CODEC='libmp3lame -b:a 320k'
+  if [ "$USE_VBR" == "y" ]; then
+    FF_AUDIO_CODEC="libmp3lame -qscale:a $OUTPUT_QUALITY"
+  else
+    FF_AUDIO_CODEC="libmp3lame -b:a $OUTPUT_BITRATE"
+  fi
   FF_CONTENT_TYPE='audio/mpeg'
   FF_OUTPUT_FORMAT='mp3'
 elif [ "$OUTPUT_FORMAT" == "ogg" ]; then
-  FF_AUDIO_CODEC='libvorbis -qscale:a 10'
+  FF_AUDIO_CODEC="libvorbis -qscale:a $OUTPUT_QUALITY"
   FF_CONTENT_TYPE='audio/ogg'
   FF_OUTPUT_FORMAT='ogg'
 elif [ "$OUTPUT_FORMAT" == "wav" ]; then
@@ -161,8 +357,75 @@ elif [ "$OUTPUT_FORMAT" == "wav" ]; then
   FF_OUTPUT_FORMAT='wav'
 fi
 
+# Downmix to mono if requested, the capture itself always stays stereo
+if [ "$OUTPUT_MONO" == "y" ]; then
+  FF_OUTPUT_CHANNELS=1
+else
+  FF_OUTPUT_CHANNELS=2
+fi
+
+# Capture from the HiFiBerry, or generate a 1 kHz sine for bench testing
+if [ "$AUDIO_INPUT" == "hifiberry" ]; then
+  FF_INPUT_ARGS='-f alsa -channels 2 -sample_rate 48000'
+  FF_INPUT_SOURCE='default:CARD=sndrpihifiberry'
+elif [ "$AUDIO_INPUT" == "testtone" ]; then
+  FF_INPUT_ARGS='-f lavfi'
+  FF_INPUT_SOURCE='sine=frequency=1000:sample_rate=48000'
+fi
+
+# Build the audio filter chain, leave it out entirely when there is nothing to do
+FF_AUDIO_FILTERS=""
+if (( INPUT_GAIN != 0 )); then
+  FF_AUDIO_FILTERS="volume=${INPUT_GAIN}dB"
+fi
+
+# The limiter comes after the gain, auto leveling would undo the ceiling
+if [ "$ENABLE_LIMITER" == "y" ]; then
+  FF_AUDIO_FILTERS="${FF_AUDIO_FILTERS:+$FF_AUDIO_FILTERS,}alimiter=limit=${LIMITER_CEILING}dB:level=disabled"
+fi
+
+if [ -n "$FF_AUDIO_FILTERS" ]; then
+  FF_FILTER_ARGS="-af $FF_AUDIO_FILTERS"
+fi
+
+# Percent-encode a string for use in a URL query parameter
+urlencode() {
+  local LC_ALL=C string="$1" encoded="" char i
+  for (( i = 0; i < ${#string}; i++ )); do
+    char="${string:i:1}"
+    case "$char" in
+      [a-zA-Z0-9.~_-]) encoded+="$char" ;;
+      *) printf -v char '%%%02X' "'$char"; encoded+="$char" ;;
+    esac
+  done
+  echo "$encoded"
+}
+
 # Define output server for ffmpeg
-FF_OUTPUT_SERVER="srt://$STREAM_HOST:$STREAM_PORT?pkt_size=1316&oheadbw=100&maxbw=-1&latency=5000000&mode=caller&transtype=live&streamid=$STREAM_MOUNTPOINT&passphrase=$STREAM_PASSWORD"
+if [ "$STREAM_PROTOCOL" == "srt" ]; then
+  # Some receivers expect the access control syntax from the SRT specification
+  if [ "$STREAM_ID_MODE" == "structured" ]; then
+    SRT_STREAM_ID=$(urlencode "#!::r=$STREAM_MOUNTPOINT,m=publish")
+  else
+    SRT_STREAM_ID=$(urlencode "$STREAM_MOUNTPOINT")
+  fi
+  # The passphrase is URL-decoded by FFmpeg, so characters like & # % or a space need encoding too
+  SRT_PASSPHRASE=$(urlencode "$STREAM_PASSWORD")
+  SRT_QUERY=""
+  for key in "${SRT_PARAM_KEYS[@]}"; do
+    SRT_QUERY+="$key=${SRT_PARAMS[$key]}&"
+  done
+  FF_OUTPUT_SERVER="srt://$STREAM_HOST:$STREAM_PORT?${SRT_QUERY}streamid=$SRT_STREAM_ID&passphrase=$SRT_PASSPHRASE&pbkeylen=$STREAM_PBKEYLEN"
+elif [ "$STREAM_PROTOCOL" == "rtmp" ]; then
+  FF_OUTPUT_SERVER="rtmp://$STREAM_HOST:$STREAM_PORT/$RTMP_APP/$RTMP_STREAM_KEY"
+  FF_OUTPUT_FORMAT='flv'
+elif [ "$STREAM_PROTOCOL" == "udp" ]; then
+  FF_OUTPUT_SERVER="udp://$STREAM_HOST:$STREAM_PORT?pkt_size=1316"
+  if [ -n "$UDP_TTL" ]; then
+    FF_OUTPUT_SERVER="$FF_OUTPUT_SERVER&ttl=$UDP_TTL"
+  fi
+  FF_OUTPUT_FORMAT='mpegts'
+fi
 
 # Add RAM disk
 if [ "$SAVE_OUTPUT" == "y" ]; then
@@ -184,10 +447,10 @@ if [ "$SAVE_OUTPUT" == "y" ]; then
   fi
 fi
 
-# Create the configuration file for supervisor
+# Create the configuration file for supervisor, a literal % must be written as %% in its config
 cat << EOF > $STREAM_CONFIG_PATH
   [program:encoder]
-  command=bash -c "sleep 30 && ffmpeg -f alsa -channels 2 -sample_rate 48000 -hide_banner -re -y -i default:CARD=sndrpihifiberry -codec:a $FF_AUDIO_CODEC -content_type $FF_CONTENT_TYPE -vn -f $FF_OUTPUT_FORMAT '$FF_OUTPUT_SERVER'"
+  command=bash -c "sleep 30 && ffmpeg $FF_INPUT_ARGS -hide_banner -re -y -i $FF_INPUT_SOURCE $FF_FILTER_ARGS -codec:a $FF_AUDIO_CODEC -ar $OUTPUT_SAMPLE_RATE -ac $FF_OUTPUT_CHANNELS -content_type $FF_CONTENT_TYPE -vn -f $FF_OUTPUT_FORMAT '${FF_OUTPUT_SERVER//%/%%}'"
   # Sleep 30 seconds before starting ffmpeg because the network or audio might not be available after a reboot. Works for now, should dig in the exact cause in the future.
   autostart=true
   autorestart=true
@@ -213,11 +476,16 @@ fi
 # Heartbeat monitoring
 if [ "$ENABLE_HEARTBEAT" == "y" ]; then
   echo -e "${BLUE}►► Setting up heartbeat monitoring...${NC}"
-  HEARTBEAT_CRONJOB="* * * * * wget --spider $HEARTBEAT_URL > /dev/null 2>&1"
-  if ! crontab -l | grep -F -- "$HEARTBEAT_CRONJOB" > /dev/null; then
-    (crontab -l 2>/dev/null; echo "$HEARTBEAT_CRONJOB") | crontab -
-  else
-    echo -e "${YELLOW}Heartbeat monitoring cronjob already exists. No changes made.${NC}"
+  # Only ping while Supervisor reports the encoder as RUNNING. This catches a manual stop or a FATAL
+  # state, not a crash loop, as the sleep before FFmpeg keeps the program RUNNING most of the time.
+  HEARTBEAT_CRONJOB="*/$HEARTBEAT_INTERVAL * * * * supervisorctl status encoder | grep -q RUNNING && wget --spider $HEARTBEAT_URL > /dev/null 2>&1"
+  if crontab -l 2>/dev/null | grep -F -- "wget --spider $HEARTBEAT_URL >" > /dev/null; then
+    echo -e "${YELLOW}Replacing the existing heartbeat monitoring cronjob.${NC}"
+  fi
+  # Drop earlier jobs for this URL, like the unconditional one from older versions or another interval
+  if ! (crontab -l 2>/dev/null | grep -vF -- "wget --spider $HEARTBEAT_URL >"; echo "$HEARTBEAT_CRONJOB") | crontab -; then
+    echo -e "${RED}Failed to install the heartbeat monitoring cronjob. Exiting...${NC}" >&2
+    exit 1
   fi
 fi
 